@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// asyncscan.go keeps large or slow directories from locking the UI
+// goroutine: a bounded worker pool does the actual ioutil.ReadDir-backed
+// scan off-thread, an LRU cache (keyed by path, validated against the
+// directory's mtime) makes re-expanding instant, and a background prefetch
+// warms the cache one level deeper than wherever the tree cursor sits so
+// hjkl navigation rarely has to wait on a cold scan at all.
+
+type dirCacheEntry struct {
+	entries []os.FileInfo
+	mtime   time.Time
+}
+
+// dirLRUCache caches sorted directory listings up to capacity entries,
+// evicting the least-recently-used directory once full.
+type dirLRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	data     map[string]dirCacheEntry
+}
+
+func newDirLRUCache(capacity int) *dirLRUCache {
+	return &dirLRUCache{capacity: capacity, data: make(map[string]dirCacheEntry)}
+}
+
+// touch moves path to the most-recently-used end of the eviction order.
+// Callers must hold c.mu.
+func (c *dirLRUCache) touch(path string) {
+	for i, p := range c.order {
+		if p == path {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, path)
+}
+
+// get returns the cached listing for path, invalidating it (and reporting
+// a miss) if the directory's mtime has moved on since it was cached.
+func (c *dirLRUCache) get(path string) ([]os.FileInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.data[path]
+	if !ok {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().Equal(entry.mtime) {
+		delete(c.data, path)
+		return nil, false
+	}
+	c.touch(path)
+	return entry.entries, true
+}
+
+// put caches entries for path, stamped with the directory's current mtime.
+func (c *dirLRUCache) put(path string, entries []os.FileInfo) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.data[path]; !exists && c.capacity > 0 && len(c.data) >= c.capacity {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.data, oldest)
+	}
+	c.data[path] = dirCacheEntry{entries: entries, mtime: info.ModTime()}
+	c.touch(path)
+}
+
+// keys returns every currently-cached directory path, used by
+// refreshLoadedDirs to know which loaded nodes need re-rendering after a
+// visibility toggle.
+func (c *dirLRUCache) keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]string, len(c.order))
+	copy(out, c.order)
+	return out
+}
+
+// scanJob is one directory-read request handed to the worker pool.
+// onCount, if set, fires once a cheap name-only listing resolves (used to
+// label the loading spinner with an entry count before the full, slower
+// ioutil.ReadDir-based scan finishes).
+type scanJob struct {
+	path    string
+	onCount func(count int)
+	done    func(entries []os.FileInfo, err error)
+}
+
+// scanPool bounds concurrent directory reads so expanding several large
+// directories at once can't spawn unbounded goroutines against a slow FS
+// backend (NFS, sshfs).
+type scanPool struct {
+	jobs chan scanJob
+}
+
+func newScanPool(workers int) *scanPool {
+	p := &scanPool{jobs: make(chan scanJob, 256)}
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *scanPool) worker() {
+	for job := range p.jobs {
+		if job.onCount != nil {
+			if f, err := os.Open(job.path); err == nil {
+				if names, err := f.Readdirnames(-1); err == nil {
+					job.onCount(len(names))
+				}
+				_ = f.Close()
+			}
+		}
+		entries, err := sortedDirEntries(job.path)
+		job.done(entries, err)
+	}
+}
+
+func (p *scanPool) submit(job scanJob) {
+	p.jobs <- job
+}
+
+// trySubmit is the non-blocking counterpart to submit: it reports whether
+// the job was queued without waiting for a worker to free up space. Callers
+// on the UI goroutine (e.g. prefetch) must use this instead of submit, since
+// a full jobs channel would otherwise block the UI until a worker drains it.
+func (p *scanPool) trySubmit(job scanJob) bool {
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// spinnerFrames is the Braille dot-spinner used while a directory loads.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// humanCount formats an entry count with thousands separators, e.g. 1234
+// -> "1,234", for the "loading N entries…" spinner label.
+func humanCount(n int) string {
+	s := fmt.Sprintf("%d", n)
+	if len(s) <= 3 {
+		return s
+	}
+	var out []byte
+	for i, c := range []byte(s) {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+// spinnerLabel holds the current loading-placeholder text behind an
+// atomic.Value so the worker pool's onCount callback and the ticker
+// goroutine animating the spinner frames can both touch it safely.
+type spinnerLabel struct {
+	v atomic.Value
+}
+
+func newSpinnerLabel(initial string) *spinnerLabel {
+	s := &spinnerLabel{}
+	s.v.Store(initial)
+	return s
+}
+
+func (s *spinnerLabel) set(text string) { s.v.Store(text) }
+func (s *spinnerLabel) get() string     { return s.v.Load().(string) }
+
+// prefetchOneLevelDeeper warms dirCache for every not-yet-cached directory
+// among node's already-listed children, one level deeper than wherever the
+// tree cursor currently sits. It never mutates a tview primitive itself —
+// it only submits background scan jobs whose result lands in dirCache for
+// next time — so it's safe to call directly from the tree's changed-node
+// callback without routing through app.QueueUpdateDraw. Submission is
+// non-blocking: tview invokes this straight from the UI goroutine, and a
+// directory with more not-yet-cached children than the jobs channel has
+// room for must never stall the UI waiting on workers to drain it, so once
+// the channel is full the rest of this pass is simply skipped and picked up
+// on a later cursor move.
+func prefetchOneLevelDeeper(pool *scanPool, cache *dirLRUCache, node *tview.TreeNode) {
+	if node == nil {
+		return
+	}
+	path, ok := node.GetReference().(string)
+	if !ok || !isDir(path) {
+		return
+	}
+	for _, child := range node.GetChildren() {
+		if child.GetText() == "(loading)" {
+			continue
+		}
+		childPath, ok := child.GetReference().(string)
+		if !ok || !isDir(childPath) {
+			continue
+		}
+		if _, cached := cache.get(childPath); cached {
+			continue
+		}
+		queued := pool.trySubmit(scanJob{
+			path: childPath,
+			done: func(entries []os.FileInfo, err error) {
+				if err == nil {
+					cache.put(childPath, entries)
+				}
+			},
+		})
+		if !queued {
+			return
+		}
+	}
+}
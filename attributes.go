@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// attributes.go adds the optional size/mode/mtime attribute columns and the
+// dotfile/gitignore/binary visibility toggles, plus the helpers that back
+// them: a simple gitignore matcher and a null-byte binary sniff.
+
+// humanSize formats a byte count the way `ls -h` roughly does, used in the
+// attribute column so large trees stay readable.
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	suffixes := "KMGTPE"
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), suffixes[exp])
+}
+
+// formatAttrColumns renders the mode/size/mtime prefix shown in the tree
+// when column display is toggled on (Ctrl+B). Widths are fixed so entries
+// line up regardless of name length.
+func formatAttrColumns(info os.FileInfo) string {
+	return fmt.Sprintf("%-10s %6s %s  ", info.Mode().String(), humanSize(info.Size()), info.ModTime().Format("2006-01-02 15:04"))
+}
+
+// isBinaryFile sniffs the first 512 bytes of path for a null byte, the same
+// heuristic net/http.DetectContentType and git itself use as a cheap
+// binary/text classifier.
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, 512)
+	n, _ := f.Read(buf)
+	for i := 0; i < n; i++ {
+		if buf[i] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// readGitignorePatterns parses one .gitignore file into cleaned pattern
+// lines, skipping blanks and comments.
+func readGitignorePatterns(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// gitignorePattern is a single collected rule plus the directory it was
+// declared in, so it can be matched against paths relative to that
+// directory rather than the tree root.
+type gitignorePattern struct {
+	base    string
+	pattern string
+	negate  bool
+}
+
+// collectGitignorePatterns walks upward from dir to stopAt (inclusive),
+// gathering .gitignore rules along the way. Rules from directories closer
+// to dir are appended last so they take precedence, matching git's
+// nearest-file-wins behavior.
+func collectGitignorePatterns(dir, stopAt string) []gitignorePattern {
+	var chain []string
+	cur := dir
+	for {
+		chain = append(chain, cur)
+		if cur == stopAt || cur == filepath.Dir(cur) {
+			break
+		}
+		cur = filepath.Dir(cur)
+	}
+	var patterns []gitignorePattern
+	for i := len(chain) - 1; i >= 0; i-- {
+		base := chain[i]
+		for _, line := range readGitignorePatterns(filepath.Join(base, ".gitignore")) {
+			negate := strings.HasPrefix(line, "!")
+			if negate {
+				line = line[1:]
+			}
+			patterns = append(patterns, gitignorePattern{base: base, pattern: line, negate: negate})
+		}
+	}
+	return patterns
+}
+
+// isGitignored reports whether path (with isDir known) matches the
+// collected pattern set, applying later rules over earlier ones like
+// gitignore's own last-match-wins semantics.
+func isGitignored(patterns []gitignorePattern, path string, isDir bool) bool {
+	ignored := false
+	for _, p := range patterns {
+		rel, err := filepath.Rel(p.base, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		pattern := p.pattern
+		dirOnly := strings.HasSuffix(pattern, "/")
+		if dirOnly {
+			pattern = strings.TrimSuffix(pattern, "/")
+			if !isDir {
+				continue
+			}
+		}
+		if matchGitignorePattern(pattern, rel) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// matchGitignorePattern matches a single gitignore-style pattern against a
+// slash-relative path, supporting a leading "**/" (match at any depth) and
+// otherwise falling back to filepath.Match against either the full relative
+// path or just its base name (gitignore treats a pattern with no slash as
+// matching at any depth too).
+func matchGitignorePattern(pattern, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if strings.HasPrefix(pattern, "**/") {
+		suffix := strings.TrimPrefix(pattern, "**/")
+		segments := strings.Split(rel, "/")
+		for i := range segments {
+			if ok, _ := filepath.Match(suffix, strings.Join(segments[i:], "/")); ok {
+				return true
+			}
+		}
+		return false
+	}
+	if strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, rel)
+		return ok
+	}
+	ok, _ := filepath.Match(pattern, filepath.Base(rel))
+	return ok
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+)
+
+// clipboard.go replaces the old wl-copy/xclip shell-outs with
+// github.com/atotto/clipboard (pbcopy/xclip/wl-copy/Windows clipboard under
+// the hood), falling back to an OSC 52 escape sequence when the library
+// reports no provider is available — the common case over SSH with no
+// DISPLAY/WAYLAND_DISPLAY, where the outer terminal (iTerm2, kitty, foot,
+// WezTerm, tmux with set-clipboard on) can still capture the payload.
+
+// oscMaxEncodedBytes caps the base64 payload written via OSC 52 so it stays
+// under the ~100KB limit many terminal emulators impose on a single escape
+// sequence.
+const oscMaxEncodedBytes = 74000
+
+// copyToClipboard copies data to the system clipboard, falling back to OSC
+// 52 if no clipboard provider is available or the copy fails. truncated
+// reports whether the OSC 52 fallback had to cut the payload to fit the
+// terminal's limit.
+func copyToClipboard(data []byte) (truncated bool, err error) {
+	if !clipboard.Unsupported {
+		if err := clipboard.WriteAll(string(data)); err == nil {
+			return false, nil
+		}
+	}
+	return copyViaOSC52(data)
+}
+
+// copyViaOSC52 base64-encodes data and emits it as an OSC 52 "set
+// clipboard" sequence directly to the terminal.
+func copyViaOSC52(data []byte) (truncated bool, err error) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if len(encoded) > oscMaxEncodedBytes {
+		encoded = encoded[:oscMaxEncodedBytes]
+		truncated = true
+	}
+	_, err = fmt.Fprintf(os.Stdout, "\x1b]52;c;%s\x07", encoded)
+	return truncated, err
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// config.go loads ~/.config/file_gather/config.yaml, modeled after dive's
+// `keybinding:` block: every action maps to a comma-separated list of keys,
+// e.g. `keybinding.toggle-selection: space, enter`. Anything the user
+// doesn't override falls back to the hardcoded defaults below, so existing
+// hjkl users see no change.
+
+// Config is the on-disk schema. All sections are optional; missing
+// entries within them fall back to defaults.
+type Config struct {
+	Keybinding    map[string]string `yaml:"keybinding"`
+	Color         map[string]string `yaml:"color"`
+	FuzzyMaxDepth int               `yaml:"fuzzy-max-depth"`
+}
+
+// defaultKeybindings gives every action its default key list. This is also
+// the authoritative list of valid action names: resolveBindings only
+// resolves actions present here.
+var defaultKeybindings = map[string]string{
+	"down":               "j, Down",
+	"up":                 "k, Up",
+	"collapse-or-parent": "h, Left",
+	"expand-or-child":    "l, Right",
+	"toggle-selection":   "space",
+	"select-glob":        "s, S",
+	"edit-patterns":      "f",
+	"filter":             "/",
+	"toggle-columns":     "Ctrl+B",
+	"toggle-hidden":      "Ctrl+A",
+	"toggle-gitignore":   "Ctrl+G",
+	"toggle-binary":      "Ctrl+N",
+	"toggle-preview":     "p",
+	"focus-tree":         "[",
+	"focus-selected":     "]",
+	"remove-selected":    "d",
+	"export":             "e",
+	"quit":               "q",
+}
+
+// defaultColors gives every themeable element its default color name, as
+// accepted by tcell.GetColor (W3C names or "#rrggbb").
+var defaultColors = map[string]string{
+	"border":    "blue",
+	"helpbar":   "blue",
+	"directory": "green",
+	"selected":  "yellow",
+	"separator": "blue",
+}
+
+// keyMatcher matches a single tcell key event, either by rune (for plain
+// character keys) or by tcell.Key (for named/control keys).
+type keyMatcher struct {
+	hasRune bool
+	r       rune
+	key     tcell.Key
+}
+
+func (m keyMatcher) matches(event *tcell.EventKey) bool {
+	if m.hasRune {
+		return event.Rune() == m.r
+	}
+	return event.Key() == m.key
+}
+
+var namedKeys = map[string]tcell.Key{
+	"enter":     tcell.KeyEnter,
+	"esc":       tcell.KeyEscape,
+	"escape":    tcell.KeyEscape,
+	"tab":       tcell.KeyTab,
+	"up":        tcell.KeyUp,
+	"down":      tcell.KeyDown,
+	"left":      tcell.KeyLeft,
+	"right":     tcell.KeyRight,
+	"backspace": tcell.KeyBackspace2,
+	"delete":    tcell.KeyDelete,
+	"home":      tcell.KeyHome,
+	"end":       tcell.KeyEnd,
+}
+
+var ctrlKeysByLetter = map[string]tcell.Key{
+	"a": tcell.KeyCtrlA, "b": tcell.KeyCtrlB, "c": tcell.KeyCtrlC, "d": tcell.KeyCtrlD,
+	"e": tcell.KeyCtrlE, "f": tcell.KeyCtrlF, "g": tcell.KeyCtrlG, "h": tcell.KeyCtrlH,
+	"i": tcell.KeyCtrlI, "j": tcell.KeyCtrlJ, "k": tcell.KeyCtrlK, "l": tcell.KeyCtrlL,
+	"m": tcell.KeyCtrlM, "n": tcell.KeyCtrlN, "o": tcell.KeyCtrlO, "p": tcell.KeyCtrlP,
+	"q": tcell.KeyCtrlQ, "r": tcell.KeyCtrlR, "s": tcell.KeyCtrlS, "t": tcell.KeyCtrlT,
+	"u": tcell.KeyCtrlU, "v": tcell.KeyCtrlV, "w": tcell.KeyCtrlW, "x": tcell.KeyCtrlX,
+	"y": tcell.KeyCtrlY, "z": tcell.KeyCtrlZ,
+}
+
+// parseKeyToken parses a single key name ("j", "space", "Ctrl+B", "Down")
+// into a keyMatcher. ok is false for tokens it doesn't recognize.
+func parseKeyToken(tok string) (m keyMatcher, ok bool) {
+	tok = strings.TrimSpace(tok)
+	if tok == "" {
+		return m, false
+	}
+	lower := strings.ToLower(tok)
+	if lower == "space" {
+		return keyMatcher{hasRune: true, r: ' '}, true
+	}
+	if key, found := namedKeys[lower]; found {
+		return keyMatcher{key: key}, true
+	}
+	if strings.HasPrefix(lower, "ctrl+") {
+		if key, found := ctrlKeysByLetter[strings.TrimPrefix(lower, "ctrl+")]; found {
+			return keyMatcher{key: key}, true
+		}
+		return m, false
+	}
+	runes := []rune(tok)
+	if len(runes) == 1 {
+		return keyMatcher{hasRune: true, r: runes[0]}, true
+	}
+	return m, false
+}
+
+// parseKeySpec parses a comma-separated key list, e.g. "j, Down", skipping
+// any token it can't recognize rather than failing the whole config.
+func parseKeySpec(spec string) []keyMatcher {
+	var matchers []keyMatcher
+	for _, tok := range strings.Split(spec, ",") {
+		if m, ok := parseKeyToken(tok); ok {
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers
+}
+
+// resolveBindings merges cfg.Keybinding over defaultKeybindings and parses
+// every action's key list.
+func resolveBindings(cfg Config) map[string][]keyMatcher {
+	bindings := make(map[string][]keyMatcher, len(defaultKeybindings))
+	for action, def := range defaultKeybindings {
+		spec := def
+		if override, ok := cfg.Keybinding[action]; ok && strings.TrimSpace(override) != "" {
+			spec = override
+		}
+		bindings[action] = parseKeySpec(spec)
+	}
+	return bindings
+}
+
+// actionMatches reports whether event triggers the named action under the
+// resolved bindings.
+func actionMatches(bindings map[string][]keyMatcher, action string, event *tcell.EventKey) bool {
+	for _, m := range bindings[action] {
+		if m.matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Colors holds the resolved, ready-to-use tcell colors for every themeable
+// element.
+type Colors struct {
+	Border    tcell.Color
+	HelpBar   tcell.Color
+	Directory tcell.Color
+	Selected  tcell.Color
+	Separator tcell.Color
+}
+
+// resolveColors merges cfg.Color over defaultColors and parses every value
+// with tcell.GetColor, which accepts both W3C color names and "#rrggbb".
+func resolveColors(cfg Config) Colors {
+	get := func(name string) tcell.Color {
+		value := defaultColors[name]
+		if override, ok := cfg.Color[name]; ok && strings.TrimSpace(override) != "" {
+			value = override
+		}
+		return tcell.GetColor(value)
+	}
+	return Colors{
+		Border:    get("border"),
+		HelpBar:   get("helpbar"),
+		Directory: get("directory"),
+		Selected:  get("selected"),
+		Separator: get("separator"),
+	}
+}
+
+// resolveFuzzyMaxDepth returns cfg's fuzzy-max-depth override, or
+// fuzzyDefaultMaxDepth if it's unset (zero) or negative.
+func resolveFuzzyMaxDepth(cfg Config) int {
+	if cfg.FuzzyMaxDepth > 0 {
+		return cfg.FuzzyMaxDepth
+	}
+	return fuzzyDefaultMaxDepth
+}
+
+// configFilePath returns ~/.config/file_gather/config.yaml (or the
+// platform equivalent via os.UserConfigDir).
+func configFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "file_gather", "config.yaml"), nil
+}
+
+// loadConfig reads and parses the config file. A missing file, or one that
+// fails to load, just yields an empty Config so callers fall back to
+// defaults — a bad config should never prevent the tool from starting.
+func loadConfig() Config {
+	var cfg Config
+	path, err := configFilePath()
+	if err != nil {
+		return cfg
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg
+	}
+	_ = yaml.Unmarshal(data, &cfg)
+	return cfg
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// export.go turns the flat list of selected files into an output buffer.
+// The "e" action (and the headless --export-format/--stdout CLI path) both
+// build a []ExportFile and hand it to whichever Exporter the user picked.
+
+// ExportFile is one selected file, already read off disk, ready to be
+// rendered by an Exporter.
+type ExportFile struct {
+	RelPath string
+	Content []byte
+	Sha256  string
+}
+
+// buildExportFiles reads every path in selected (relative to root) into an
+// ExportFile, recording a hex sha256 of its content. Read errors are
+// recorded as the file's content rather than aborting the whole export, to
+// match the existing "error reading file: %v" behavior in the tree pane.
+func buildExportFiles(root string, selected []string, readFile func(string) ([]byte, error)) []ExportFile {
+	files := make([]ExportFile, 0, len(selected))
+	for _, p := range selected {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			rel = p
+		}
+		content, err := readFile(p)
+		if err != nil {
+			content = []byte(fmt.Sprintf("error reading file: %v\n", err))
+		}
+		sum := sha256.Sum256(content)
+		files = append(files, ExportFile{RelPath: rel, Content: content, Sha256: hex.EncodeToString(sum[:])})
+	}
+	return files
+}
+
+// Exporter renders a set of selected files into a single output buffer.
+// Name is what the export format picker shows the user.
+type Exporter interface {
+	Name() string
+	Export(files []ExportFile) ([]byte, error)
+}
+
+// markdownExporter reproduces the tool's original fenced-code-block format:
+// relative path, then a ``` fence around the file's content.
+type markdownExporter struct{}
+
+func (markdownExporter) Name() string { return "Markdown" }
+
+func (markdownExporter) Export(files []ExportFile) ([]byte, error) {
+	var buf strings.Builder
+	for _, f := range files {
+		buf.WriteString(f.RelPath)
+		buf.WriteString("\n\n```\n")
+		buf.Write(f.Content)
+		if len(f.Content) == 0 || f.Content[len(f.Content)-1] != '\n' {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("```\n\n")
+	}
+	return []byte(buf.String()), nil
+}
+
+// jsonExporter emits a JSON array of {path, content, sha256} objects.
+type jsonExporter struct{}
+
+func (jsonExporter) Name() string { return "JSON" }
+
+func (jsonExporter) Export(files []ExportFile) ([]byte, error) {
+	type entry struct {
+		Path    string `json:"path"`
+		Content string `json:"content"`
+		Sha256  string `json:"sha256"`
+	}
+	entries := make([]entry, 0, len(files))
+	for _, f := range files {
+		entries = append(entries, entry{Path: f.RelPath, Content: string(f.Content), Sha256: f.Sha256})
+	}
+	return json.MarshalIndent(entries, "", "  ")
+}
+
+// xmlExporter emits <files><file path="...">...</file></files>.
+type xmlExporter struct{}
+
+func (xmlExporter) Name() string { return "XML" }
+
+func (xmlExporter) Export(files []ExportFile) ([]byte, error) {
+	type xmlFile struct {
+		Path    string `xml:"path,attr"`
+		Sha256  string `xml:"sha256,attr"`
+		Content string `xml:",chardata"`
+	}
+	type xmlFiles struct {
+		XMLName xml.Name  `xml:"files"`
+		Files   []xmlFile `xml:"file"`
+	}
+	root := xmlFiles{}
+	for _, f := range files {
+		root.Files = append(root.Files, xmlFile{Path: f.RelPath, Sha256: f.Sha256, Content: string(f.Content)})
+	}
+	out, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// languageForExt maps a file extension to the fenced-code-block language
+// tag the chat exporter uses, falling back to the bare extension name.
+func languageForExt(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js":
+		return "javascript"
+	case ".ts", ".tsx":
+		return "typescript"
+	case ".rs":
+		return "rust"
+	case ".java":
+		return "java"
+	case ".c", ".h":
+		return "c"
+	case ".cpp", ".cc", ".hpp":
+		return "cpp"
+	case ".sh", ".bash":
+		return "bash"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".md":
+		return "markdown"
+	case ".sql":
+		return "sql"
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
+
+// buildDirectoryTreeSummary renders a minimal indented tree of relPaths,
+// used as a table-of-contents header in the chat-optimized export so a
+// model gets the shape of the selection before the file bodies.
+func buildDirectoryTreeSummary(relPaths []string) string {
+	sorted := append([]string(nil), relPaths...)
+	sort.Strings(sorted)
+	var buf strings.Builder
+	buf.WriteString("Selected files:\n")
+	for _, p := range sorted {
+		depth := strings.Count(filepath.ToSlash(p), "/")
+		buf.WriteString(strings.Repeat("  ", depth))
+		buf.WriteString("- ")
+		buf.WriteString(p)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// estimateTokens is a cheap, provider-agnostic approximation (~4 bytes per
+// token) used only to decide where the chat exporter's budget runs out.
+func estimateTokens(n int) int {
+	return n / 4
+}
+
+// chatExporter emits per-file ```lang fences prefixed by a directory-tree
+// summary, truncating the middle of any file that would blow the overall
+// token budget.
+type chatExporter struct {
+	TokenBudget int // 0 means unlimited
+}
+
+func (chatExporter) Name() string { return "Chat (LLM)" }
+
+func (c chatExporter) Export(files []ExportFile) ([]byte, error) {
+	relPaths := make([]string, len(files))
+	for i, f := range files {
+		relPaths[i] = f.RelPath
+	}
+	var buf strings.Builder
+	buf.WriteString(buildDirectoryTreeSummary(relPaths))
+	buf.WriteString("\n")
+
+	budget := c.TokenBudget
+	spent := estimateTokens(buf.Len())
+	for _, f := range files {
+		lang := languageForExt(filepath.Ext(f.RelPath))
+		content := f.Content
+		if budget > 0 {
+			remaining := budget - spent
+			if remaining <= 0 {
+				buf.WriteString(fmt.Sprintf("%s\n... [omitted: token budget exhausted] ...\n\n", f.RelPath))
+				continue
+			}
+			maxBytes := remaining * 4
+			if len(content) > maxBytes {
+				content = truncateMiddle(content, maxBytes)
+			}
+		}
+		buf.WriteString(f.RelPath)
+		buf.WriteString("\n```")
+		buf.WriteString(lang)
+		buf.WriteString("\n")
+		buf.Write(content)
+		if len(content) == 0 || content[len(content)-1] != '\n' {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("```\n\n")
+		spent += estimateTokens(len(content))
+	}
+	return []byte(buf.String()), nil
+}
+
+// truncateMiddle keeps the first and last quarter of content (roughly) and
+// replaces the middle with a "[N bytes omitted]" marker, so callers still
+// see a file's imports/signature at the top and its tail at the bottom.
+func truncateMiddle(content []byte, maxBytes int) []byte {
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return content
+	}
+	half := maxBytes / 2
+	omitted := len(content) - maxBytes
+	marker := []byte(fmt.Sprintf("\n... [%d bytes omitted] ...\n", omitted))
+	var out []byte
+	out = append(out, content[:half]...)
+	out = append(out, marker...)
+	out = append(out, content[len(content)-half:]...)
+	return out
+}
+
+// exportersByFlag maps the --export-format CLI value (and the export
+// format picker's button labels, e.g. "Chat (LLM)") to an Exporter
+// instance. Matching is case-insensitive and ignores any parenthesized
+// suffix on the label, so picker button text can stay human-readable
+// without falling out of sync with the flag values this also accepts.
+func exportersByFlag(name string, tokenBudget int) (Exporter, error) {
+	normalized := strings.ToLower(name)
+	if i := strings.Index(normalized, "("); i != -1 {
+		normalized = normalized[:i]
+	}
+	normalized = strings.TrimSpace(normalized)
+	switch normalized {
+	case "markdown", "md":
+		return markdownExporter{}, nil
+	case "json":
+		return jsonExporter{}, nil
+	case "xml":
+		return xmlExporter{}, nil
+	case "chat", "llm":
+		return chatExporter{TokenBudget: tokenBudget}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q (want markdown, json, xml, or chat)", name)
+	}
+}
+
+// runHeadlessExport implements the --stdout CLI path: export the given file
+// paths (relative to the current working directory) with the requested
+// format and write the result straight to stdout, without starting the TUI.
+func runHeadlessExport(format string, tokenBudget int, paths []string) {
+	if len(paths) == 0 {
+		log.Fatalf("--stdout requires one or more file paths as arguments")
+	}
+	exporter, err := exportersByFlag(format, tokenBudget)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("error getting current directory: %v", err)
+	}
+	files := buildExportFiles(cwd, paths, ioutil.ReadFile)
+	output, err := exporter.Export(files)
+	if err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+	os.Stdout.Write(output)
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+// TestExportersByFlagMatchesModalButtons drives exportersByFlag with the
+// literal button labels from the export-format modal in main.go, so a
+// label/switch mismatch (like "Chat (LLM)" never matching "chat"/"llm")
+// fails here instead of only surfacing as an error modal in the TUI.
+func TestExportersByFlagMatchesModalButtons(t *testing.T) {
+	buttons := map[string]string{
+		"Markdown":   "Markdown",
+		"JSON":       "JSON",
+		"XML":        "XML",
+		"Chat (LLM)": "Chat (LLM)",
+	}
+	for label, want := range buttons {
+		exporter, err := exportersByFlag(label, 0)
+		if err != nil {
+			t.Errorf("exportersByFlag(%q) returned error: %v", label, err)
+			continue
+		}
+		if exporter.Name() != want {
+			t.Errorf("exportersByFlag(%q).Name() = %q, want %q", label, exporter.Name(), want)
+		}
+	}
+}
+
+func TestExportersByFlagCLIValues(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"markdown", "Markdown"},
+		{"md", "Markdown"},
+		{"json", "JSON"},
+		{"xml", "XML"},
+		{"chat", "Chat (LLM)"},
+		{"llm", "Chat (LLM)"},
+		{"CHAT", "Chat (LLM)"},
+	}
+	for _, tt := range tests {
+		exporter, err := exportersByFlag(tt.flag, 0)
+		if err != nil {
+			t.Errorf("exportersByFlag(%q) returned error: %v", tt.flag, err)
+			continue
+		}
+		if exporter.Name() != tt.want {
+			t.Errorf("exportersByFlag(%q).Name() = %q, want %q", tt.flag, exporter.Name(), tt.want)
+		}
+	}
+}
+
+func TestExportersByFlagUnknown(t *testing.T) {
+	if _, err := exportersByFlag("yaml", 0); err == nil {
+		t.Errorf("exportersByFlag(%q) expected an error, got nil", "yaml")
+	}
+}
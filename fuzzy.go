@@ -0,0 +1,168 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// fuzzy.go implements the subsequence-match scoring and bounded subtree walk
+// used by the "/" incremental filter in the tree pane.
+
+// fuzzyEntry is a single candidate surfaced by walkSubtree: a file or
+// directory path relative to the walk root, plus whether it's a directory.
+type fuzzyEntry struct {
+	path  string
+	isDir bool
+}
+
+const (
+	fuzzyDefaultMaxDepth = 8
+	fuzzyMaxResults      = 200
+)
+
+// fuzzyScore scores text against pattern using subsequence matching. Every
+// rune of pattern (case-insensitively) must appear in text in order; ok is
+// false if any rune can't be matched. Consecutive matches, and matches that
+// land on a camelCase boundary or right after a path separator, score extra
+// so that "fg" ranks "file_gather.go" above "foo/bar.go".
+func fuzzyScore(pattern, text string) (score int, ok bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	runes := []rune(text)
+	pat := []rune(strings.ToLower(pattern))
+	lower := []rune(strings.ToLower(text))
+
+	pi := 0
+	lastMatch := -2
+	for ti := 0; ti < len(lower) && pi < len(pat); ti++ {
+		if lower[ti] != pat[pi] {
+			continue
+		}
+		gain := 1
+		if ti == lastMatch+1 {
+			gain += 5 // consecutive run
+		}
+		if ti == 0 || runes[ti-1] == filepath.Separator || runes[ti-1] == '/' {
+			gain += 4 // path-separator boundary
+		} else if unicode.IsUpper(runes[ti]) && ti > 0 && unicode.IsLower(runes[ti-1]) {
+			gain += 3 // camelCase boundary
+		}
+		score += gain
+		lastMatch = ti
+		pi++
+	}
+	if pi != len(pat) {
+		return 0, false
+	}
+	return score, true
+}
+
+// walkSubtree lazily walks the directory tree rooted at root, up to
+// maxDepth levels deep, returning every file and directory encountered.
+// It reuses sortedDirEntries directly, with no caching of its own, so it
+// does a fresh read of every directory on the walk. That makes it cheap to
+// reason about but unsuitable to call from the UI goroutine on anything
+// but a small subtree — callers driving the "/" filter should use
+// walkSubtreeAsync instead, which shares the tree pane's cache and worker
+// pool and is meant to be run off the UI goroutine, once per filter
+// session rather than once per keystroke.
+func walkSubtree(root string, maxDepth int) []fuzzyEntry {
+	var results []fuzzyEntry
+	var walk func(path string, depth int)
+	walk = func(path string, depth int) {
+		entries, err := sortedDirEntries(path)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			childPath := filepath.Join(path, e.Name())
+			results = append(results, fuzzyEntry{path: childPath, isDir: e.IsDir()})
+			if e.IsDir() && depth < maxDepth {
+				walk(childPath, depth+1)
+			}
+		}
+	}
+	walk(root, 0)
+	return results
+}
+
+// walkSubtreeAsync walks the same way walkSubtree does, but every
+// directory's listing goes through cache first (an LRU hit skips the read
+// entirely, reusing whatever the tree pane has already scanned) and a miss
+// is filled by pool, the same bounded worker pool asyncscan.go uses for
+// the tree pane, instead of a direct, unbounded ioutil.ReadDir. It still
+// blocks its caller until the walk finishes, so it must only be called
+// from a background goroutine — never the UI goroutine — with the result
+// delivered back via app.QueueUpdateDraw, the same pattern
+// previewController uses.
+func walkSubtreeAsync(pool *scanPool, cache *dirLRUCache, root string, maxDepth int) []fuzzyEntry {
+	var results []fuzzyEntry
+	var walk func(path string, depth int)
+	walk = func(path string, depth int) {
+		entries, ok := cache.get(path)
+		if !ok {
+			var wg sync.WaitGroup
+			wg.Add(1)
+			pool.submit(scanJob{
+				path: path,
+				done: func(e []os.FileInfo, err error) {
+					if err == nil {
+						entries = e
+						cache.put(path, e)
+					}
+					wg.Done()
+				},
+			})
+			wg.Wait()
+		}
+		for _, e := range entries {
+			childPath := filepath.Join(path, e.Name())
+			results = append(results, fuzzyEntry{path: childPath, isDir: e.IsDir()})
+			if e.IsDir() && depth < maxDepth {
+				walk(childPath, depth+1)
+			}
+		}
+	}
+	walk(root, 0)
+	return results
+}
+
+// fuzzyMatch pairs a candidate entry with the score it earned against the
+// current query.
+type fuzzyMatch struct {
+	entry fuzzyEntry
+	score int
+}
+
+// topFuzzyMatches scores every entry against query and returns the top n
+// matches sorted by descending score (ties broken by path for stable
+// ordering). An empty query matches everything in tree order.
+func topFuzzyMatches(entries []fuzzyEntry, root, query string, n int) []fuzzyMatch {
+	var matches []fuzzyMatch
+	for _, e := range entries {
+		rel, err := filepath.Rel(root, e.path)
+		if err != nil {
+			rel = e.path
+		}
+		score, ok := fuzzyScore(query, rel)
+		if !ok {
+			continue
+		}
+		matches = append(matches, fuzzyMatch{entry: e, score: score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.path < matches[j].entry.path
+	})
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
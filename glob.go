@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// glob.go implements gitignore-style include/exclude glob evaluation (with
+// "**" matching zero or more path segments) for the recursive directory
+// selection feature, plus loading/saving the user's last-used pattern set.
+
+// globToRegexp compiles a single glob pattern into a regexp anchored to the
+// full string. A lone "*" stops at a separator, and "?" matches one
+// non-separator rune. "**" matches across path separators; when it is
+// followed by "/" (e.g. the leading "**/*.go") that separator is optional,
+// so the pattern also matches paths with nothing before it (gitignore's
+// "**/" semantics), and likewise "/**" at the end of a pattern optionally
+// matches nothing.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '*':
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				switch {
+				case i+2 < len(runes) && runes[i+2] == '/':
+					b.WriteString("(?:.*/)?")
+					i += 2
+				case i == 0 && i+2 == len(runes):
+					b.WriteString(".*")
+					i++
+				case i > 0 && runes[i-1] == '/' && i+2 == len(runes):
+					// preceding "/" already written; make it optional too.
+					s := b.String()
+					b.Reset()
+					b.WriteString(strings.TrimSuffix(s, "/"))
+					b.WriteString("(?:/.*)?")
+					i++
+				default:
+					b.WriteString(".*")
+					i++
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// evaluateGlobRules applies rules to relPath in order, gitignore-style:
+// each rule that matches sets the included verdict, with a leading "!"
+// meaning "exclude"; later rules win over earlier ones. A path with no
+// matching rule is excluded.
+func evaluateGlobRules(rules []string, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	included := false
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		negate := strings.HasPrefix(rule, "!")
+		pattern := rule
+		if negate {
+			pattern = rule[1:]
+		}
+		re, err := globToRegexp(pattern)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(relPath) {
+			included = !negate
+		}
+	}
+	return included
+}
+
+// collectFilesRecursive returns every regular file beneath root, depth
+// unbounded, in the same sorted order the tree pane uses.
+func collectFilesRecursive(root string) []string {
+	var files []string
+	var walk func(path string)
+	walk = func(path string) {
+		entries, err := sortedDirEntries(path)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			childPath := filepath.Join(path, e.Name())
+			if e.IsDir() {
+				walk(childPath)
+			} else {
+				files = append(files, childPath)
+			}
+		}
+	}
+	walk(root)
+	return files
+}
+
+// patternsFilePath returns ~/.config/file_gather/patterns (or the
+// platform equivalent via os.UserConfigDir), where the last-used glob
+// pattern set is persisted.
+func patternsFilePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "file_gather", "patterns"), nil
+}
+
+// loadPatterns reads the persisted pattern set, one pattern per line,
+// ignoring blank lines. A missing file is not an error — it just means no
+// pattern set has been saved yet.
+func loadPatterns() []string {
+	path, err := patternsFilePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+// savePatterns persists patterns to patternsFilePath, creating the config
+// directory if needed.
+func savePatterns(patterns []string) error {
+	path, err := patternsFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(patterns, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestGlobToRegexpMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "sub/main.go", true},
+		{"**/*.go", "a/b/main.go", true},
+		{"**/*.go", "main.txt", false},
+		{"*.go", "main.go", true},
+		{"*.go", "sub/main.go", false},
+		{"foo/**", "foo", true},
+		{"foo/**", "foo/bar", true},
+		{"foo/**", "foo/bar/baz", true},
+		{"foo/**", "other", false},
+		{"**/vendor/**", "vendor/x.go", true},
+		{"**/vendor/**", "a/vendor/b/c.go", true},
+	}
+	for _, tt := range tests {
+		re, err := globToRegexp(tt.pattern)
+		if err != nil {
+			t.Fatalf("globToRegexp(%q) error: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("globToRegexp(%q).MatchString(%q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateGlobRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		path  string
+		want  bool
+	}{
+		{
+			name:  "grab all Go source at any depth, including repo root",
+			rules: []string{"**/*.go"},
+			path:  "main.go",
+			want:  true,
+		},
+		{
+			name:  "later negation wins over earlier inclusion",
+			rules: []string{"**/*.go", "!**/vendor/**"},
+			path:  "vendor/pkg/file.go",
+			want:  false,
+		},
+		{
+			name:  "no matching rule excludes by default",
+			rules: []string{"**/*.go"},
+			path:  "README.md",
+			want:  false,
+		},
+		{
+			name:  "later inclusion wins back over an earlier exclusion",
+			rules: []string{"!**/*_test.go", "**/*.go"},
+			path:  "fuzzy_test.go",
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluateGlobRules(tt.rules, tt.path); got != tt.want {
+				t.Errorf("evaluateGlobRules(%v, %q) = %v, want %v", tt.rules, tt.path, got, tt.want)
+			}
+		})
+	}
+}
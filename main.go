@@ -1,9 +1,8 @@
 package main
 
 import (
-	"bytes"
+	"flag"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
 	"os"
@@ -11,6 +10,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -25,10 +25,11 @@ import (
 // - [ and ] : switch focus between left and right panes
 // - Right pane: list of selected files
 // - d (on right): remove file from selection
-// - e (lowercase): write relative path + contents to a temporary file and either open in gedit or copy the concatenated content to the clipboard (user choice)
+// - e (lowercase): pick an export format (Markdown/JSON/XML/Chat), then either open the result in gedit or copy it to the clipboard (user choice)
 // - q: exit the application
 // - Bottom helpbar shows key help (with colored background)
 // - UI: overall colored border (blue), vertical separator between panes
+// - --export-format/--stdout let File Gather export a list of file paths headlessly, without starting the TUI
 
 func isDir(path string) bool {
 	info, err := os.Stat(path)
@@ -57,11 +58,27 @@ func sortedDirEntries(path string) ([]os.FileInfo, error) {
 }
 
 func main() {
+	exportFormat := flag.String("export-format", "markdown", "export format: markdown, json, xml, or chat")
+	stdout := flag.Bool("stdout", false, "headless mode: export the given file paths to stdout using --export-format and exit")
+	tokenBudget := flag.Int("token-budget", 8000, "token budget for the chat export format (0 = unlimited)")
+	flag.Parse()
+
+	if *stdout {
+		runHeadlessExport(*exportFormat, *tokenBudget, flag.Args())
+		return
+	}
+
 	startDir, err := os.Getwd()
 	if err != nil {
 		log.Fatalf("error getting current directory: %v", err)
 	}
 
+	// cfg holds the user's ~/.config/file_gather/config.yaml (or defaults
+	// if absent); bindings/colors are the parsed, ready-to-use forms.
+	cfg := loadConfig()
+	bindings := resolveBindings(cfg)
+	colors := resolveColors(cfg)
+
 	app := tview.NewApplication()
 
 	// node maps for quick access and parent tracking
@@ -76,6 +93,9 @@ func main() {
 		if _, ok := selectedMap[path]; ok {
 			return
 		}
+		if node, ok := nodeMap[path]; ok {
+			node.SetColor(colors.Selected)
+		}
 		selectedMap[path] = struct{}{}
 		selectedList = append(selectedList, path)
 	}
@@ -84,6 +104,9 @@ func main() {
 		if _, ok := selectedMap[path]; !ok {
 			return
 		}
+		if node, ok := nodeMap[path]; ok {
+			node.SetColor(tcell.ColorWhite)
+		}
 		delete(selectedMap, path)
 		newList := make([]string, 0, len(selectedList)-1)
 		for _, p := range selectedList {
@@ -94,6 +117,16 @@ func main() {
 		selectedList = newList
 	}
 
+	// currentPatterns holds the last-used include/exclude glob rules for
+	// recursive directory selection (Shift+Space / "s"), seeded from the
+	// previous session's saved patterns if any.
+	currentPatterns := loadPatterns()
+
+	// openPatternModal ("f") lets the user edit the comma-separated
+	// include/exclude glob rules used by recursive selection; assigned
+	// once tree exists (forward-decl style, same as addChildren above).
+	var openPatternModal func()
+
 	// selected list view (right)
 	selectedListView := tview.NewList()
 	selectedListView.ShowSecondaryText(false)
@@ -113,37 +146,165 @@ func main() {
 		SetColor(tcell.ColorYellow)
 	nodeMap[startDir] = rootNode
 
+	// dirCache holds per-directory listings in a bounded LRU, validated
+	// against each directory's mtime, so toggling a visibility filter
+	// (Ctrl+A/G/N) or the column display (Ctrl+B) only re-renders
+	// already-loaded nodes instead of re-reading the filesystem, and
+	// re-expanding a directory is instant as long as it hasn't changed on
+	// disk. gitignoreCache/binaryCache memoize their own checks the same
+	// way. scanPool does the actual directory reads off the UI goroutine,
+	// for expandNodeAsync below.
+	dirCache := newDirLRUCache(512)
+	gitignoreCache := make(map[string][]gitignorePattern)
+	binaryCache := make(map[string]bool)
+	scanPool := newScanPool(4)
+
+	// visibility/display toggles, all off by default so existing hjkl
+	// users see the same tree they always have.
+	showColumns := false
+	hideHidden := false
+	hideGitignored := false
+	hideBinary := false
+
 	// lazy-load children
 	var addChildren func(node *tview.TreeNode, path string)
 	addChildren = func(node *tview.TreeNode, path string) {
 		node.ClearChildren()
-		entries, err := sortedDirEntries(path)
-		if err != nil {
-			// unable to read -> leave empty
-			return
+		entries, ok := dirCache.get(path)
+		if !ok {
+			var err error
+			entries, err = sortedDirEntries(path)
+			if err != nil {
+				// unable to read -> leave empty
+				return
+			}
+			dirCache.put(path, entries)
+		}
+		patterns, ok := gitignoreCache[path]
+		if !ok && hideGitignored {
+			patterns = collectGitignorePatterns(path, startDir)
+			gitignoreCache[path] = patterns
 		}
 		for _, e := range entries {
 			childPath := filepath.Join(path, e.Name())
+			if hideHidden && strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			if hideGitignored && isGitignored(patterns, childPath, e.IsDir()) {
+				continue
+			}
+			if hideBinary && !e.IsDir() {
+				bin, ok := binaryCache[childPath]
+				if !ok {
+					bin = isBinaryFile(childPath)
+					binaryCache[childPath] = bin
+				}
+				if bin {
+					continue
+				}
+			}
 			var label string
 			if e.IsDir() {
 				label = fmt.Sprintf("[DIR] %s", e.Name())
 			} else {
 				label = e.Name()
 			}
+			if showColumns {
+				label = formatAttrColumns(e) + label
+			}
 			child := tview.NewTreeNode(label).
 				SetReference(childPath).
 				SetSelectable(true)
 			nodeMap[childPath] = child
 			parentMap[childPath] = path
 			if e.IsDir() {
-				child.SetColor(tcell.ColorGreen)
+				child.SetColor(colors.Directory)
 				// placeholder to indicate expandable
 				child.AddChild(tview.NewTreeNode("(loading)"))
+			} else if _, ok := selectedMap[childPath]; ok {
+				child.SetColor(colors.Selected)
 			}
 			node.AddChild(child)
 		}
 	}
 
+	// expandNodeAsync is the interactive entry point for opening a directory
+	// node that hasn't been scanned yet: a cached directory still populates
+	// synchronously (instant), but a cold one shows an animated "(loading)"
+	// placeholder and does the actual read on scanPool, only touching the
+	// tree again via app.QueueUpdateDraw once the result is in. after, if
+	// given, runs once the node's children are in place (e.g. to Expand()
+	// it). This is what keeps opening a directory with thousands of entries
+	// from freezing the UI goroutine.
+	expandNodeAsync := func(node *tview.TreeNode, path string, after func()) {
+		if _, ok := dirCache.get(path); ok {
+			addChildren(node, path)
+			if after != nil {
+				after()
+			}
+			return
+		}
+		label := newSpinnerLabel("loading…")
+		frame := 0
+		render := func() {
+			node.ClearChildren()
+			node.AddChild(tview.NewTreeNode(fmt.Sprintf("%c %s", spinnerFrames[frame%len(spinnerFrames)], label.get())))
+		}
+		render()
+		stop := make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(120 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					frame++
+					app.QueueUpdateDraw(render)
+				}
+			}
+		}()
+		scanPool.submit(scanJob{
+			path: path,
+			onCount: func(count int) {
+				label.set(fmt.Sprintf("loading %s entries…", humanCount(count)))
+			},
+			done: func(entries []os.FileInfo, err error) {
+				close(stop)
+				app.QueueUpdateDraw(func() {
+					if err == nil {
+						dirCache.put(path, entries)
+					}
+					addChildren(node, path)
+					if after != nil {
+						after()
+					}
+				})
+			},
+		})
+	}
+
+	// refreshLoadedDirs re-renders every directory that's already been
+	// scanned, using the caches above, so flipping a toggle never re-stats
+	// the filesystem.
+	refreshLoadedDirs := func() {
+		for _, path := range dirCache.keys() {
+			node, ok := nodeMap[path]
+			if !ok {
+				continue
+			}
+			if len(node.GetChildren()) == 1 && node.GetChildren()[0].GetText() == "(loading)" {
+				continue // never expanded past the placeholder; nothing to refresh
+			}
+			wasExpanded := node.IsExpanded()
+			addChildren(node, path)
+			if wasExpanded {
+				node.Expand()
+			}
+		}
+	}
+
 	// initial load of root children
 	addChildren(rootNode, startDir)
 
@@ -151,6 +312,119 @@ func main() {
 		SetRoot(rootNode).
 		SetCurrentNode(rootNode)
 
+	// previewView/previewCtl back the optional third pane ("p") that shows a
+	// syntax-highlighted preview of whatever file the cursor is on; see
+	// preview.go. previewVisible tracks whether it's currently reflowed into
+	// the layout below.
+	previewView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	previewView.SetBorder(true).SetTitle(" Preview ")
+	previewCtl := newPreviewController(previewView)
+	previewVisible := false
+
+	// body and previewSeparator are assembled further down alongside the
+	// rest of the layout; forward-declared here (same as openFilter/
+	// closeFilter elsewhere in this function) so togglePreview below can
+	// reflow them via body.ResizeItem.
+	var body *tview.Flex
+	var previewSeparator *tview.Box
+
+	// togglePreview flips the preview pane in/out of the body layout via
+	// ResizeItem, populating it immediately from whatever the tree cursor is
+	// currently on. Wired into both the tree's and the selected-files list's
+	// input captures, since the pane's own visibility isn't tied to which
+	// pane has focus.
+	togglePreview := func() {
+		previewVisible = !previewVisible
+		if previewVisible {
+			body.ResizeItem(previewSeparator, 1, 0)
+			body.ResizeItem(previewView, 0, 2)
+			if cur := tree.GetCurrentNode(); cur != nil {
+				if ref := cur.GetReference(); ref != nil {
+					previewCtl.showNow(app, ref.(string))
+				}
+			}
+		} else {
+			body.ResizeItem(previewSeparator, 0, 0)
+			body.ResizeItem(previewView, 0, 0)
+		}
+	}
+
+	// Background prefetch: every time the cursor lands on a new node, warm
+	// dirCache for its already-listed child directories one level deeper,
+	// so hjkl navigation rarely hits a cold scanPool read. Also, if the
+	// preview pane is open, debounce a re-render for the newly focused file
+	// so holding j/k doesn't spawn a highlight per keystroke.
+	tree.SetChangedFunc(func(node *tview.TreeNode) {
+		prefetchOneLevelDeeper(scanPool, dirCache, node)
+		if !previewVisible || node == nil {
+			return
+		}
+		if ref := node.GetReference(); ref != nil {
+			previewCtl.request(app, ref.(string))
+		}
+	})
+
+	// ensureLoaded walks nodeMap/parentMap up from path to startDir and
+	// makes sure every directory along the way has had addChildren run,
+	// so jumping to a fuzzy-filter result always lands on a real node.
+	var ensureLoaded func(path string)
+	ensureLoaded = func(path string) {
+		if path == startDir {
+			return
+		}
+		parent := filepath.Dir(path)
+		ensureLoaded(parent)
+		parentNode, ok := nodeMap[parent]
+		if !ok {
+			return
+		}
+		if len(parentNode.GetChildren()) == 1 && parentNode.GetChildren()[0].GetText() == "(loading)" {
+			addChildren(parentNode, parent)
+		}
+		parentNode.Expand()
+	}
+
+	// openFilter and closeFilter are assigned once the bottom bar and
+	// overlay widgets exist further down; declared here (forward-decl
+	// style, same as addChildren above) so the tree input capture can
+	// reference them.
+	var openFilter func(cur *tview.TreeNode)
+	var closeFilter func()
+
+	openPatternModal = func() {
+		form := tview.NewForm()
+		form.AddInputField("Patterns (comma-separated, ! to exclude)", strings.Join(currentPatterns, ", "), 60, nil, nil)
+		form.AddButton("Apply & Save", func() {
+			text := form.GetFormItem(0).(*tview.InputField).GetText()
+			var patterns []string
+			for _, p := range strings.Split(text, ",") {
+				p = strings.TrimSpace(p)
+				if p != "" {
+					patterns = append(patterns, p)
+				}
+			}
+			currentPatterns = patterns
+			if err := savePatterns(patterns); err != nil {
+				modal := tview.NewModal().
+					SetText(fmt.Sprintf("Patterns applied, but failed to save: %v", err)).
+					AddButtons([]string{"OK"}).
+					SetDoneFunc(func(int, string) {
+						app.SetRoot(frameWrapper, true).SetFocus(tree)
+					})
+				app.SetRoot(modal, false)
+				return
+			}
+			app.SetRoot(frameWrapper, true).SetFocus(tree)
+		})
+		form.AddButton("Cancel", func() {
+			app.SetRoot(frameWrapper, true).SetFocus(tree)
+		})
+		form.SetBorder(true).SetTitle(" Selection filter (Shift+Space / s to apply) ")
+		app.SetRoot(form, true)
+	}
+
 	// helper: move to parent based on parentMap
 	moveToParent := func(cur *tview.TreeNode) {
 		if cur == nil {
@@ -209,9 +483,10 @@ func main() {
 		}
 		path := ref.(string)
 		if isDir(path) {
-			// lazy load if needed
+			// lazy load if needed (async: see expandNodeAsync)
 			if len(node.GetChildren()) == 1 && node.GetChildren()[0].GetText() == "(loading)" {
-				addChildren(node, path)
+				expandNodeAsync(node, path, func() { node.Expand() })
+				return
 			}
 			if node.IsExpanded() {
 				node.Collapse()
@@ -234,20 +509,18 @@ func main() {
 		if event == nil {
 			return event
 		}
-		// quick exit
-		if event.Rune() == 'q' {
+		switch {
+		case actionMatches(bindings, "quit", event):
 			app.Stop()
 			return nil
-		}
-		switch event.Rune() {
-		case 'j':
+		case actionMatches(bindings, "down", event):
 			// delegate to default handler for down key
 			tree.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
 			return nil
-		case 'k':
+		case actionMatches(bindings, "up", event):
 			tree.InputHandler()(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), nil)
 			return nil
-		case 'h':
+		case actionMatches(bindings, "collapse-or-parent", event):
 			cur := tree.GetCurrentNode()
 			if cur == nil {
 				return nil
@@ -259,7 +532,7 @@ func main() {
 				moveToParent(cur)
 			}
 			return nil
-		case 'l':
+		case actionMatches(bindings, "expand-or-child", event):
 			cur := tree.GetCurrentNode()
 			if cur == nil {
 				return nil
@@ -270,9 +543,11 @@ func main() {
 			}
 			path := ref.(string)
 			if isDir(path) {
-				// lazy load then expand / go to first child
+				// lazy load (async: see expandNodeAsync) then expand / go to
+				// first child
 				if len(cur.GetChildren()) == 1 && cur.GetChildren()[0].GetText() == "(loading)" {
-					addChildren(cur, path)
+					expandNodeAsync(cur, path, func() { cur.Expand() })
+					return nil
 				}
 				if !cur.IsExpanded() {
 					cur.Expand()
@@ -281,8 +556,7 @@ func main() {
 				}
 			}
 			return nil
-		case ' ':
-			// toggle selection if file
+		case actionMatches(bindings, "toggle-selection", event):
 			cur := tree.GetCurrentNode()
 			if cur == nil {
 				return nil
@@ -301,43 +575,61 @@ func main() {
 				refreshSelectedView()
 			}
 			return nil
-		case '[':
+		case actionMatches(bindings, "focus-tree", event):
 			app.SetFocus(tree)
 			return nil
-		case ']':
+		case actionMatches(bindings, "focus-selected", event):
 			app.SetFocus(selectedListView)
 			return nil
-		}
-
-		// handle arrow keys for expand/collapse fallback
-		switch event.Key() {
-		case tcell.KeyRight:
+		case actionMatches(bindings, "filter", event):
+			openFilter(tree.GetCurrentNode())
+			return nil
+		case actionMatches(bindings, "select-glob", event):
+			// recursive directory selection, constrained by currentPatterns
 			cur := tree.GetCurrentNode()
 			if cur == nil {
-				return event
+				return nil
 			}
 			ref := cur.GetReference()
 			if ref == nil {
-				return event
+				return nil
 			}
 			path := ref.(string)
-			if isDir(path) {
-				if len(cur.GetChildren()) == 1 && cur.GetChildren()[0].GetText() == "(loading)" {
-					addChildren(cur, path)
+			if !isDir(path) {
+				return nil
+			}
+			for _, filePath := range collectFilesRecursive(path) {
+				if len(currentPatterns) > 0 {
+					rel, err := filepath.Rel(path, filePath)
+					if err != nil || !evaluateGlobRules(currentPatterns, rel) {
+						continue
+					}
 				}
-				cur.Expand()
+				addSelected(filePath)
 			}
+			refreshSelectedView()
 			return nil
-		case tcell.KeyLeft:
-			cur := tree.GetCurrentNode()
-			if cur == nil {
-				return event
-			}
-			if cur.IsExpanded() {
-				cur.Collapse()
-			} else {
-				moveToParent(cur)
-			}
+		case actionMatches(bindings, "edit-patterns", event):
+			openPatternModal()
+			return nil
+		case actionMatches(bindings, "toggle-columns", event):
+			showColumns = !showColumns
+			refreshLoadedDirs()
+			return nil
+		case actionMatches(bindings, "toggle-hidden", event):
+			hideHidden = !hideHidden
+			refreshLoadedDirs()
+			return nil
+		case actionMatches(bindings, "toggle-gitignore", event):
+			hideGitignored = !hideGitignored
+			refreshLoadedDirs()
+			return nil
+		case actionMatches(bindings, "toggle-binary", event):
+			hideBinary = !hideBinary
+			refreshLoadedDirs()
+			return nil
+		case actionMatches(bindings, "toggle-preview", event):
+			togglePreview()
 			return nil
 		}
 
@@ -352,19 +644,17 @@ func main() {
 		if event == nil {
 			return event
 		}
-		// quick exit
-		if event.Rune() == 'q' {
+		switch {
+		case actionMatches(bindings, "quit", event):
 			app.Stop()
 			return nil
-		}
-		switch event.Rune() {
-		case 'j':
+		case actionMatches(bindings, "down", event):
 			selectedListView.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
 			return nil
-		case 'k':
+		case actionMatches(bindings, "up", event):
 			selectedListView.InputHandler()(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), nil)
 			return nil
-		case 'd':
+		case actionMatches(bindings, "remove-selected", event):
 			// remove current item
 			index := selectedListView.GetCurrentItem()
 			if index < 0 || index >= len(selectedList) {
@@ -380,14 +670,17 @@ func main() {
 				selectedListView.SetCurrentItem(index)
 			}
 			return nil
-		case '[':
+		case actionMatches(bindings, "toggle-preview", event):
+			togglePreview()
+			return nil
+		case actionMatches(bindings, "focus-tree", event):
 			app.SetFocus(tree)
 			return nil
-		case ']':
+		case actionMatches(bindings, "focus-selected", event):
 			app.SetFocus(selectedListView)
 			return nil
-		case 'e':
-			// Option: open in gedit OR copy to clipboard
+		case actionMatches(bindings, "export", event):
+			// Option: pick an export format, then open in gedit OR copy to clipboard
 			if len(selectedList) == 0 {
 				modal := tview.NewModal().
 					SetText("No files selected.").
@@ -398,149 +691,119 @@ func main() {
 				app.SetRoot(modal, false)
 				return nil
 			}
-			// present a choice modal
-			choice := tview.NewModal().
-				SetText("Open selected files in gedit or copy combined content to clipboard?").
-				AddButtons([]string{"Gedit", "Copy to clipboard", "Cancel"}).
+			formatModal := tview.NewModal().
+				SetText("Export format?").
+				AddButtons([]string{"Markdown", "JSON", "XML", "Chat (LLM)", "Cancel"}).
 				SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-					app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
-					if buttonLabel == "Gedit" {
-						// prepare buffer and temp file
-						var buf bytes.Buffer
-						for _, p := range selectedList {
-							rel, err := filepath.Rel(startDir, p)
-							if err != nil {
-								rel = p
-							}
-							buf.WriteString(rel)
-							buf.WriteString("\n\n")
-							buf.WriteString("```\n")
-							content, err := ioutil.ReadFile(p)
-							if err != nil {
-								buf.WriteString(fmt.Sprintf("error reading file: %v\n", err))
-							} else {
-								buf.Write(content)
-								if len(content) == 0 || content[len(content)-1] != '\n' {
-									buf.WriteString("\n")
-								}
-							}
-							buf.WriteString("```\n\n")
-						}
-						tmp, err := ioutil.TempFile("", "treepicker_selected_*.txt")
-						if err != nil {
-							modalErr := tview.NewModal().
-								SetText(fmt.Sprintf("Error creating temporary file: %v", err)).
-								AddButtons([]string{"OK"}).
-								SetDoneFunc(func(int, string) {
-									app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
-								})
-							app.SetRoot(modalErr, false)
-							return
-						}
-						_, err = io.Copy(tmp, &buf)
-						if err != nil {
-							_ = tmp.Close()
-							modalErr := tview.NewModal().
-								SetText(fmt.Sprintf("Error writing temporary file: %v", err)).
-								AddButtons([]string{"OK"}).
-								SetDoneFunc(func(int, string) {
-									app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
-								})
-							app.SetRoot(modalErr, false)
-							return
-						}
-						_ = tmp.Close()
-						// try to open gedit
-						cmd := exec.Command("gedit", tmp.Name())
-						if err := cmd.Start(); err != nil {
-							// fallback xdg-open
-							fb := exec.Command("xdg-open", tmp.Name())
-							if err2 := fb.Start(); err2 != nil {
-								modalErr := tview.NewModal().
-									SetText(fmt.Sprintf("Error opening editor: %v\n(fallback xdg-open also failed: %v)", err, err2)).
-									AddButtons([]string{"OK"}).
-									SetDoneFunc(func(int, string) {
-										app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
-									})
-								app.SetRoot(modalErr, false)
-								return
-							}
-						}
-						modalDone := tview.NewModal().
-							SetText(fmt.Sprintf("Temporary file created: %s\n(Editor started)", tmp.Name())).
+					if buttonLabel == "Cancel" || buttonLabel == "" {
+						app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+						return
+					}
+					exporter, err := exportersByFlag(buttonLabel, *tokenBudget)
+					if err != nil {
+						modalErr := tview.NewModal().
+							SetText(fmt.Sprintf("Error: %v", err)).
 							AddButtons([]string{"OK"}).
 							SetDoneFunc(func(int, string) {
 								app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
 							})
-						app.SetRoot(modalDone, false)
-					} else if buttonLabel == "Copy to clipboard" {
-						// prepare combined buffer
-						var buf bytes.Buffer
-						for _, p := range selectedList {
-							rel, err := filepath.Rel(startDir, p)
-							if err != nil {
-								rel = p
-							}
-							buf.WriteString(rel)
-							buf.WriteString("\n\n")
-							content, err := ioutil.ReadFile(p)
-							if err != nil {
-								buf.WriteString(fmt.Sprintf("error reading file: %v\n", err))
-							} else {
-								buf.Write(content)
-								if len(content) == 0 || content[len(content)-1] != '\n' {
-									buf.WriteString("\n")
-								}
-							}
-							buf.WriteString("\n---\n\n")
-						}
-						// try wl-copy then xclip
-						copied := false
-						if cmd := exec.Command("wl-copy"); cmd != nil {
-							stdin, err := cmd.StdinPipe()
-							if err == nil {
-								if err := cmd.Start(); err == nil {
-									_, _ = io.Copy(stdin, &buf)
-									_ = stdin.Close()
-									_ = cmd.Wait()
-									copied = true
-								}
-							}
-						}
-						if !copied {
-							// try xclip -selection clipboard
-							buf2 := bytes.NewBuffer(buf.Bytes())
-							cmd := exec.Command("xclip", "-selection", "clipboard")
-							stdin, err := cmd.StdinPipe()
-							if err == nil {
-								if err := cmd.Start(); err == nil {
-									_, _ = io.Copy(stdin, buf2)
-									_ = stdin.Close()
-									_ = cmd.Wait()
-									copied = true
-								}
-							}
-						}
-						if !copied {
-							modalErr := tview.NewModal().
-								SetText("Failed to copy to clipboard: neither wl-copy nor xclip succeeded or were available.").
-								AddButtons([]string{"OK"}).
-								SetDoneFunc(func(int, string) {
-									app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
-								})
-							app.SetRoot(modalErr, false)
-							return
-						}
-						modalDone := tview.NewModal().
-							SetText("Combined content copied to clipboard.").
+						app.SetRoot(modalErr, false)
+						return
+					}
+					files := buildExportFiles(startDir, selectedList, ioutil.ReadFile)
+					output, err := exporter.Export(files)
+					if err != nil {
+						modalErr := tview.NewModal().
+							SetText(fmt.Sprintf("Error building export: %v", err)).
 							AddButtons([]string{"OK"}).
 							SetDoneFunc(func(int, string) {
 								app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
 							})
-						app.SetRoot(modalDone, false)
+						app.SetRoot(modalErr, false)
+						return
 					}
+					// present a choice modal
+					choice := tview.NewModal().
+						SetText("Open exported content in gedit or copy it to the clipboard?").
+						AddButtons([]string{"Gedit", "Copy to clipboard", "Cancel"}).
+						SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+							app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+							if buttonLabel == "Gedit" {
+								tmp, err := ioutil.TempFile("", "treepicker_selected_*.txt")
+								if err != nil {
+									modalErr := tview.NewModal().
+										SetText(fmt.Sprintf("Error creating temporary file: %v", err)).
+										AddButtons([]string{"OK"}).
+										SetDoneFunc(func(int, string) {
+											app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+										})
+									app.SetRoot(modalErr, false)
+									return
+								}
+								_, err = tmp.Write(output)
+								if err != nil {
+									_ = tmp.Close()
+									modalErr := tview.NewModal().
+										SetText(fmt.Sprintf("Error writing temporary file: %v", err)).
+										AddButtons([]string{"OK"}).
+										SetDoneFunc(func(int, string) {
+											app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+										})
+									app.SetRoot(modalErr, false)
+									return
+								}
+								_ = tmp.Close()
+								// try to open gedit
+								cmd := exec.Command("gedit", tmp.Name())
+								if err := cmd.Start(); err != nil {
+									// fallback xdg-open
+									fb := exec.Command("xdg-open", tmp.Name())
+									if err2 := fb.Start(); err2 != nil {
+										modalErr := tview.NewModal().
+											SetText(fmt.Sprintf("Error opening editor: %v\n(fallback xdg-open also failed: %v)", err, err2)).
+											AddButtons([]string{"OK"}).
+											SetDoneFunc(func(int, string) {
+												app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+											})
+										app.SetRoot(modalErr, false)
+										return
+									}
+								}
+								modalDone := tview.NewModal().
+									SetText(fmt.Sprintf("Temporary file created: %s\n(Editor started)", tmp.Name())).
+									AddButtons([]string{"OK"}).
+									SetDoneFunc(func(int, string) {
+										app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+									})
+								app.SetRoot(modalDone, false)
+							} else if buttonLabel == "Copy to clipboard" {
+								truncated, err := copyToClipboard(output)
+								if err != nil {
+									modalErr := tview.NewModal().
+										SetText(fmt.Sprintf("Failed to copy to clipboard: %v", err)).
+										AddButtons([]string{"OK"}).
+										SetDoneFunc(func(int, string) {
+											app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+										})
+									app.SetRoot(modalErr, false)
+									return
+								}
+								doneText := "Exported content copied to clipboard."
+								if truncated {
+									doneText = "Copied via OSC 52, but the payload was too large for the terminal's limit and was truncated."
+								}
+								modalDone := tview.NewModal().
+									SetText(doneText).
+									AddButtons([]string{"OK"}).
+									SetDoneFunc(func(int, string) {
+										app.SetRoot(frameWrapper, true).SetFocus(selectedListView)
+									})
+								app.SetRoot(modalDone, false)
+							}
+						})
+					app.SetRoot(choice, false)
 				})
-			app.SetRoot(choice, false)
+			app.SetRoot(formatModal, false)
 			return nil
 		}
 		return event
@@ -551,34 +814,189 @@ func main() {
 		SetDynamicColors(true).
 		SetRegions(false).
 		SetWrap(false)
-	help.SetBackgroundColor(tcell.ColorBlue)
-	fmt.Fprint(help, "Keys: h/j/k/l navigate  |  Space toggle selection  |  [ and ] change focus  |  d remove (right)  |  e open/copy  |  q quit")
+	help.SetBackgroundColor(colors.HelpBar)
+	fmt.Fprint(help, "Keys: h/j/k/l navigate  |  Space toggle selection  |  s select dir (glob)  |  f patterns  |  [ and ] change focus  |  / filter  |  p preview  |  ^B cols ^A hidden ^G gitignore ^N binary  |  d remove (right)  |  e open/copy  |  q quit")
+
+	// filter input replaces the help bar while "/" incremental search is
+	// active; filterResultsList temporarily replaces the tree pane inside
+	// leftPane so the rest of the layout doesn't need to shuffle.
+	filterInput := tview.NewInputField().
+		SetLabel("/ ").
+		SetFieldBackgroundColor(tcell.ColorBlack)
+	filterResultsList := tview.NewList()
+	filterResultsList.ShowSecondaryText(false)
+	filterResultsList.SetBorder(true).SetTitle(" Filter results ")
+
+	// bottomBar hosts either help or filterInput, swapped on "/" and Esc.
+	bottomBar := tview.NewFlex().SetDirection(tview.FlexColumn)
+	bottomBar.AddItem(help, 0, 1, false)
+
+	// leftPane hosts either tree or filterResultsList, swapped on "/" and Esc.
+	leftPane := tview.NewFlex().SetDirection(tview.FlexRow)
+	leftPane.AddItem(tree, 0, 1, true)
+
+	filterMaxDepth := resolveFuzzyMaxDepth(cfg)
+
+	var filterRoot string
+	var filterMatches []fuzzyMatch
+	// filterEntries is the subtree walk for the current filterRoot, loaded
+	// once per filter session (see loadFilterEntries) rather than on every
+	// keystroke: applyFilterQuery only re-scores this already-in-memory
+	// slice, so typing a query never touches the filesystem.
+	var filterEntries []fuzzyEntry
+
+	applyFilterQuery := func(query string) {
+		filterMatches = topFuzzyMatches(filterEntries, filterRoot, query, fuzzyMaxResults)
+		filterResultsList.Clear()
+		for _, m := range filterMatches {
+			rel, err := filepath.Rel(filterRoot, m.entry.path)
+			if err != nil {
+				rel = m.entry.path
+			}
+			label := rel
+			if m.entry.isDir {
+				label = "[DIR] " + rel
+			}
+			if _, ok := selectedMap[m.entry.path]; ok {
+				label = "[x] " + label
+			}
+			filterResultsList.AddItem(label, "", 0, nil)
+		}
+	}
+
+	// loadFilterEntries (re)populates filterEntries for root in the
+	// background via walkSubtreeAsync, then re-scores query against the
+	// freshly loaded entries once they land. Called once per filter
+	// session (on open), not per keystroke.
+	loadFilterEntries := func(root, query string) {
+		filterEntries = nil
+		filterResultsList.Clear()
+		filterResultsList.AddItem("loading…", "", 0, nil)
+		go func() {
+			entries := walkSubtreeAsync(scanPool, dirCache, root, filterMaxDepth)
+			app.QueueUpdateDraw(func() {
+				if root != filterRoot {
+					return // filter was reopened against a different root meanwhile
+				}
+				filterEntries = entries
+				applyFilterQuery(query)
+			})
+		}()
+	}
+
+	jumpToFilterResult := func(index int) {
+		if index < 0 || index >= len(filterMatches) {
+			return
+		}
+		path := filterMatches[index].entry.path
+		ensureLoaded(path)
+		if node, ok := nodeMap[path]; ok {
+			tree.SetCurrentNode(node)
+		}
+		closeFilter()
+	}
+
+	closeFilter = func() {
+		bottomBar.Clear()
+		bottomBar.AddItem(help, 0, 1, false)
+		leftPane.Clear()
+		leftPane.AddItem(tree, 0, 1, true)
+		app.SetFocus(tree)
+	}
 
-	// Layout: body (left tree, separator, right list) + help bottom
-	body := tview.NewFlex().SetDirection(tview.FlexColumn)
+	openFilter = func(cur *tview.TreeNode) {
+		path := startDir
+		if cur != nil {
+			if ref := cur.GetReference(); ref != nil {
+				path = ref.(string)
+			}
+		}
+		if !isDir(path) {
+			path = filepath.Dir(path)
+		}
+		filterRoot = path
+		filterInput.SetText("")
+		loadFilterEntries(path, "")
+		bottomBar.Clear()
+		bottomBar.AddItem(filterInput, 0, 1, true)
+		leftPane.Clear()
+		leftPane.AddItem(filterResultsList, 0, 1, true)
+		app.SetFocus(filterInput)
+	}
+
+	filterInput.SetChangedFunc(applyFilterQuery)
+	filterInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeFilter()
+			return nil
+		case tcell.KeyEnter:
+			jumpToFilterResult(filterResultsList.GetCurrentItem())
+			return nil
+		case tcell.KeyDown:
+			app.SetFocus(filterResultsList)
+			return nil
+		}
+		return event
+	})
+	filterResultsList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		jumpToFilterResult(index)
+	})
+	filterResultsList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeFilter()
+			return nil
+		}
+		if event.Rune() == ' ' {
+			index := filterResultsList.GetCurrentItem()
+			if index >= 0 && index < len(filterMatches) {
+				entry := filterMatches[index].entry
+				if !entry.isDir {
+					if _, ok := selectedMap[entry.path]; ok {
+						removeSelected(entry.path)
+					} else {
+						addSelected(entry.path)
+					}
+					refreshSelectedView()
+					applyFilterQuery(filterInput.GetText())
+				}
+			}
+			return nil
+		}
+		return event
+	})
+
+	// Layout: body (left pane, separator, right list) + bottom bar
+	body = tview.NewFlex().SetDirection(tview.FlexColumn)
 
 	// separator column: thin vertical colored bar
 	separator := tview.NewBox()
-	separator.SetBackgroundColor(tcell.ColorBlue)
+	separator.SetBackgroundColor(colors.Separator)
+	previewSeparator = tview.NewBox()
+	previewSeparator.SetBackgroundColor(colors.Separator)
 
 	// give borders to left and right panes and titles
 	tree.SetBorder(true).SetTitle(" Tree ")
 	selectedListView.SetBorder(true).SetTitle(" Selected files ")
 
-	// assemble body: tree | separator | selected list
-	body.AddItem(tree, 0, 3, true)
+	// assemble body: left pane | separator | selected list | (preview,
+	// hidden until "p" toggles it in via body.ResizeItem above)
+	body.AddItem(leftPane, 0, 3, true)
 	body.AddItem(separator, 1, 0, false) // thin vertical column
 	body.AddItem(selectedListView, 0, 2, false)
+	body.AddItem(previewSeparator, 0, 0, false)
+	body.AddItem(previewView, 0, 0, false)
 
 	// wrap main layout in a frame with colored border and title (tool name)
 	layout = tview.NewFlex().SetDirection(tview.FlexRow)
 	layout.AddItem(body, 0, 1, true)
-	layout.AddItem(help, 1, 1, false)
+	layout.AddItem(bottomBar, 1, 1, false)
 
 	frame := tview.NewFrame(layout)
 	frame.SetBorders(0, 0, 0, 0, 0, 0)
 	frame.SetBorder(true)
-	frame.SetBorderColor(tcell.ColorBlue)
+	frame.SetBorderColor(colors.Border)
 	frame.SetTitle(" File Gather ")
 
 	frameWrapper = frame
@@ -595,4 +1013,3 @@ func main() {
 // package-level variables used by modal callbacks
 var layout *tview.Flex
 var frameWrapper *tview.Frame
-
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/rivo/tview"
+)
+
+// preview.go backs the optional third pane ("p" to toggle) that shows a
+// syntax-highlighted preview of whatever file the tree cursor is on. Reading
+// and tokenizing happen off the UI goroutine (previewController.request
+// debounces to a single read per 200ms of cursor movement, same idea as
+// asyncscan.go's scanPool), with the result delivered back via
+// app.QueueUpdateDraw.
+
+const (
+	previewDebounce      = 200 * time.Millisecond
+	previewSizeThreshold = 1 << 20 // 1 MiB: above this, only previewReadLimit is read
+	previewReadLimit     = 64 << 10
+	previewHexDumpBytes  = 512
+	previewStyle         = "monokai"
+)
+
+// previewController owns the debounce timer for preview updates: rapid j/k
+// navigation restarts the timer instead of piling up reads, so scrolling
+// through a directory doesn't spawn a highlight per keystroke.
+type previewController struct {
+	view *tview.TextView
+
+	mu    sync.Mutex
+	timer *time.Timer
+	// generation is bumped on every request so a slow, in-flight render
+	// from a since-abandoned path can't clobber a newer one when it lands.
+	generation uint64
+}
+
+func newPreviewController(view *tview.TextView) *previewController {
+	return &previewController{view: view}
+}
+
+// request schedules a re-render of path, debounced by previewDebounce.
+func (p *previewController) request(app *tview.Application, path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	p.generation++
+	gen := p.generation
+	p.timer = time.AfterFunc(previewDebounce, func() {
+		content := renderPreview(path)
+		app.QueueUpdateDraw(func() {
+			p.mu.Lock()
+			stale := gen != p.generation
+			p.mu.Unlock()
+			if stale {
+				return
+			}
+			p.view.Clear()
+			fmt.Fprint(p.view, content)
+			p.view.ScrollToBeginning()
+		})
+	})
+}
+
+// showNow renders path without waiting out the debounce timer, but still off
+// the UI goroutine. Used when the preview pane is first toggled on, so it
+// doesn't start out blank.
+func (p *previewController) showNow(app *tview.Application, path string) {
+	p.mu.Lock()
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.generation++
+	gen := p.generation
+	p.mu.Unlock()
+	go func() {
+		content := renderPreview(path)
+		app.QueueUpdateDraw(func() {
+			p.mu.Lock()
+			stale := gen != p.generation
+			p.mu.Unlock()
+			if stale {
+				return
+			}
+			p.view.Clear()
+			fmt.Fprint(p.view, content)
+			p.view.ScrollToBeginning()
+		})
+	}()
+}
+
+// renderPreview builds the dynamic-color markup shown in the preview pane
+// for path: a directory notice, a hex dump for binaries, or a
+// syntax-highlighted (and, past previewSizeThreshold, truncated) view of a
+// text file's contents.
+func renderPreview(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Sprintf("[red]error: %s", tview.Escape(err.Error()))
+	}
+	if info.IsDir() {
+		return "[gray]directory — no preview"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Sprintf("[red]error: %s", tview.Escape(err.Error()))
+	}
+	defer f.Close()
+
+	readLimit := info.Size()
+	truncated := false
+	if readLimit > previewSizeThreshold {
+		readLimit = previewReadLimit
+		truncated = true
+	}
+	buf := make([]byte, readLimit)
+	n, _ := f.Read(buf)
+	buf = buf[:n]
+
+	if isBinaryFile(path) {
+		dumpLen := n
+		if dumpLen > previewHexDumpBytes {
+			dumpLen = previewHexDumpBytes
+		}
+		mime := http.DetectContentType(buf)
+		var b strings.Builder
+		fmt.Fprintf(&b, "[gray]%s, %s[-]\n\n", mime, humanSize(info.Size()))
+		b.WriteString(tview.Escape(hexDump(buf[:dumpLen])))
+		if info.Size() > int64(dumpLen) {
+			fmt.Fprintf(&b, "\n\n[gray](showing first %d of %s bytes)[-]", dumpLen, humanSize(info.Size()))
+		}
+		return b.String()
+	}
+
+	highlighted, err := highlightSource(path, buf)
+	if err != nil {
+		highlighted = tview.Escape(string(buf))
+	}
+	if truncated {
+		highlighted += fmt.Sprintf("\n\n[gray](showing first %s of %s)[-]", humanSize(previewReadLimit), humanSize(info.Size()))
+	}
+	return highlighted
+}
+
+// highlightSource tokenizes source with the lexer chroma picks for path's
+// filename (falling back to content analysis) and renders it as tview
+// dynamic-color markup using the previewStyle theme.
+func highlightSource(path string, source []byte) (string, error) {
+	lexer := lexers.Match(path)
+	if lexer == nil {
+		lexer = lexers.Analyse(string(source))
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+	style := styles.Get(previewStyle)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	iterator, err := lexer.Tokenise(nil, string(source))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, token := range iterator.Tokens() {
+		entry := style.Get(token.Type)
+		text := tview.Escape(token.Value)
+		if !entry.Colour.IsSet() {
+			b.WriteString(text)
+			continue
+		}
+		fmt.Fprintf(&b, "[%s]%s[-]", entry.Colour.String(), text)
+	}
+	return b.String(), nil
+}
+
+// hexDump renders data as a `hexdump -C`-style dump: 16 bytes per line,
+// hex on the left and the printable ASCII rendering on the right.
+func hexDump(data []byte) string {
+	var b strings.Builder
+	for offset := 0; offset < len(data); offset += 16 {
+		end := offset + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[offset:end]
+		fmt.Fprintf(&b, "%08x  ", offset)
+		for i := 0; i < 16; i++ {
+			if i < len(chunk) {
+				fmt.Fprintf(&b, "%02x ", chunk[i])
+			} else {
+				b.WriteString("   ")
+			}
+			if i == 7 {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(" |")
+		for _, c := range chunk {
+			if c >= 32 && c < 127 {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteString("|\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}